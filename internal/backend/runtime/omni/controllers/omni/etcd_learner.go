@@ -0,0 +1,234 @@
+// Copyright (c) 2025 Sidero Labs, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the LICENSE file.
+
+package omni
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/cosi-project/runtime/pkg/state"
+	talosclient "github.com/siderolabs/talos/pkg/machinery/client"
+	"go.uber.org/zap"
+
+	"github.com/siderolabs/omni/client/pkg/omni/resources"
+	"github.com/siderolabs/omni/client/pkg/omni/resources/omni"
+	"github.com/siderolabs/omni/internal/backend/runtime/omni/pkg/check"
+)
+
+// etcdPeerPort is the etcd peer (as opposed to client) port Talos listens on.
+const etcdPeerPort = "2380"
+
+// EtcdLearnerController drives control plane scale-up: it adds new control plane machines to etcd as
+// learners rather than full voting members, and promotes them once they have caught up, so a freshly
+// joined member can never take a healthy cluster below quorum while it's still syncing.
+//
+//nolint:govet
+type EtcdLearnerController struct{}
+
+// NewEtcdLearnerController creates new EtcdLearnerController.
+func NewEtcdLearnerController() *EtcdLearnerController {
+	return &EtcdLearnerController{}
+}
+
+// Name implements controller.Controller interface.
+func (ctrl *EtcdLearnerController) Name() string {
+	return "EtcdLearnerController"
+}
+
+// Inputs implements controller.Controller interface.
+func (ctrl *EtcdLearnerController) Inputs() []controller.Input {
+	return []controller.Input{
+		{
+			Namespace: resources.DefaultNamespace,
+			Type:      omni.MachineSetType,
+			Kind:      controller.InputWeak,
+		},
+		{
+			Namespace: resources.DefaultNamespace,
+			Type:      omni.ClusterMachineStatusType,
+			Kind:      controller.InputWeak,
+		},
+		{
+			Namespace: resources.DefaultNamespace,
+			Type:      omni.ClusterMachineIdentityType,
+			Kind:      controller.InputWeak,
+		},
+	}
+}
+
+// Outputs implements controller.Controller interface.
+func (ctrl *EtcdLearnerController) Outputs() []controller.Output {
+	return []controller.Output{
+		{
+			Type: omni.ClusterMachineIdentityType,
+			Kind: controller.OutputShared,
+		},
+	}
+}
+
+// Run implements controller.Controller interface.
+func (ctrl *EtcdLearnerController) Run(ctx context.Context, r controller.Runtime, logger *zap.Logger) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-r.EventCh():
+		}
+
+		machineSets, err := safe.ReaderListAll[*omni.MachineSet](ctx, r, state.WithLabelQuery(
+			resource.LabelExists(omni.LabelControlPlaneRole),
+		))
+		if err != nil {
+			return err
+		}
+
+		for machineSet := range machineSets.All() {
+			if err = ctrl.reconcileMachineSet(ctx, r, logger, machineSet); err != nil {
+				logger.Warn("etcd learner reconcile failed", zap.String("machineset", machineSet.Metadata().ID()), zap.Error(err))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(time.Minute):
+		}
+	}
+}
+
+func (ctrl *EtcdLearnerController) reconcileMachineSet(ctx context.Context, r controller.Runtime, logger *zap.Logger, machineSet *omni.MachineSet) error {
+	clusterName, ok := machineSet.Metadata().Labels().Get(omni.LabelCluster)
+	if !ok {
+		return fmt.Errorf("machine set %q doesn't have the %s label", machineSet.Metadata().ID(), omni.LabelCluster)
+	}
+
+	status, err := check.EtcdStatus(ctx, r, machineSet)
+	if err != nil {
+		return err
+	}
+
+	clusterMachineStatuses, err := safe.ReaderListAll[*omni.ClusterMachineStatus](ctx, r, state.WithLabelQuery(
+		resource.LabelEqual(omni.LabelMachineSet, machineSet.Metadata().ID()),
+	))
+	if err != nil {
+		return err
+	}
+
+	for clusterMachineStatus := range clusterMachineStatuses.All() {
+		machineID := clusterMachineStatus.Metadata().ID()
+
+		if _, connected := clusterMachineStatus.Metadata().Labels().Get(omni.MachineStatusLabelConnected); !connected {
+			continue
+		}
+
+		if clusterMachineStatus.TypedSpec().Value.ManagementAddress == "" {
+			continue
+		}
+
+		if _, joined := status.Members[machineID]; joined {
+			if err = ctrl.promoteMember(ctx, r, logger, clusterName, status, machineID); err != nil {
+				logger.Warn("failed to promote etcd learner", zap.String("machine", machineID), zap.Error(err))
+			}
+
+			continue
+		}
+
+		if err = ctrl.addLearner(ctx, r, logger, clusterName, status, clusterMachineStatus); err != nil {
+			logger.Warn("failed to add etcd learner", zap.String("machine", machineID), zap.Error(err))
+		}
+	}
+
+	return nil
+}
+
+func (ctrl *EtcdLearnerController) addLearner(
+	ctx context.Context, r controller.ReaderWriter, logger *zap.Logger,
+	clusterName string, status *check.EtcdStatusResult, clusterMachineStatus *omni.ClusterMachineStatus,
+) error {
+	machineID := clusterMachineStatus.Metadata().ID()
+
+	host, _, err := net.SplitHostPort(clusterMachineStatus.TypedSpec().Value.ManagementAddress)
+	if err != nil {
+		host = clusterMachineStatus.TypedSpec().Value.ManagementAddress
+	}
+
+	peerURL := fmt.Sprintf("https://%s", net.JoinHostPort(host, etcdPeerPort))
+
+	return ctrl.withHealthyMemberClient(ctx, r, clusterName, machineID, status, func(ctx context.Context, talosClient *talosclient.Client) error {
+		logger.Info("adding etcd learner", zap.String("machine", machineID), zap.String("peer_url", peerURL))
+
+		return check.AddLearnerMember(ctx, r, talosClient, machineID, peerURL)
+	})
+}
+
+func (ctrl *EtcdLearnerController) promoteMember(
+	ctx context.Context, r controller.Reader, logger *zap.Logger,
+	clusterName string, status *check.EtcdStatusResult, machineID string,
+) error {
+	identity, err := safe.ReaderGet[*omni.ClusterMachineIdentity](ctx, r, omni.NewClusterMachineIdentity(resources.DefaultNamespace, machineID).Metadata())
+	if err != nil {
+		if state.IsNotFoundError(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	memberID := identity.TypedSpec().Value.EtcdMemberId
+	if memberID == 0 {
+		return nil
+	}
+
+	return ctrl.withHealthyMemberClient(ctx, r, clusterName, machineID, status, func(ctx context.Context, talosClient *talosclient.Client) error {
+		err := check.PromoteLearner(ctx, talosClient, memberID, check.DefaultLearnerPromotionThreshold)
+		if check.IsLearnerPromoting(err) {
+			logger.Info("etcd learner still catching up", zap.String("machine", machineID), zap.Error(err))
+
+			return nil
+		}
+
+		return err
+	})
+}
+
+// withHealthyMemberClient runs fn against the Talos client of a healthy control plane member that is
+// not excludeMachineID, since both EtcdMemberAdd and EtcdMemberPromote must be issued against an
+// already-established member, not the one being joined or promoted.
+func (ctrl *EtcdLearnerController) withHealthyMemberClient(
+	ctx context.Context, r controller.Reader, clusterName, excludeMachineID string, status *check.EtcdStatusResult,
+	fn func(ctx context.Context, talosClient *talosclient.Client) error,
+) error {
+	for candidateID, member := range status.Members {
+		if candidateID == excludeMachineID || !member.Healthy {
+			continue
+		}
+
+		candidateStatus, err := safe.ReaderGet[*omni.ClusterMachineStatus](ctx, r, omni.NewClusterMachineStatus(resources.DefaultNamespace, candidateID).Metadata())
+		if err != nil {
+			continue
+		}
+
+		talosClient, err := check.BuildTalosClient(ctx, r, clusterName, candidateStatus)
+		if err != nil {
+			continue
+		}
+
+		err = fn(ctx, talosClient)
+
+		talosClient.Close() //nolint:errcheck
+
+		return err
+	}
+
+	return fmt.Errorf("no healthy etcd member available to act on behalf of machine %q", excludeMachineID)
+}
+
+var _ controller.Controller = &EtcdLearnerController{}
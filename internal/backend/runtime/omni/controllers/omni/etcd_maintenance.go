@@ -0,0 +1,309 @@
+// Copyright (c) 2025 Sidero Labs, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the LICENSE file.
+
+package omni
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/robfig/cron/v3"
+	"github.com/siderolabs/talos/pkg/machinery/api/machine"
+	"github.com/siderolabs/talos/pkg/machinery/client"
+	"go.uber.org/zap"
+
+	"github.com/siderolabs/omni/client/pkg/omni/resources"
+	"github.com/siderolabs/omni/client/pkg/omni/resources/omni"
+	"github.com/siderolabs/omni/internal/backend/runtime/omni/pkg/check"
+)
+
+// defaultFragmentationRatioThreshold is used when an EtcdMaintenanceConfig doesn't set one explicitly.
+const defaultFragmentationRatioThreshold = 0.5
+
+// etcdHealthRecoveryTimeout bounds how long reconcileCluster waits for etcd to report healthy again
+// between defragging two members, so a single stuck member doesn't wedge the controller forever.
+const etcdHealthRecoveryTimeout = 2 * time.Minute
+
+// etcdHealthRecoveryPollInterval is how often health is re-checked while waiting for recovery.
+const etcdHealthRecoveryPollInterval = 5 * time.Second
+
+// EtcdMaintenanceController periodically defragments etcd on control plane members that have opted in
+// via an EtcdMaintenanceConfig, and disarms NOSPACE alarms once a defrag has brought the database back
+// under quota.
+//
+//nolint:govet
+type EtcdMaintenanceController struct{}
+
+// NewEtcdMaintenanceController creates new EtcdMaintenanceController.
+func NewEtcdMaintenanceController() *EtcdMaintenanceController {
+	return &EtcdMaintenanceController{}
+}
+
+// Name implements controller.Controller interface.
+func (ctrl *EtcdMaintenanceController) Name() string {
+	return "EtcdMaintenanceController"
+}
+
+// Inputs implements controller.Controller interface.
+func (ctrl *EtcdMaintenanceController) Inputs() []controller.Input {
+	return []controller.Input{
+		{
+			Namespace: resources.DefaultNamespace,
+			Type:      omni.EtcdMaintenanceConfigType,
+			Kind:      controller.InputWeak,
+		},
+		{
+			Namespace: resources.DefaultNamespace,
+			Type:      omni.ControlPlaneStatusType,
+			Kind:      controller.InputWeak,
+		},
+		{
+			Namespace: resources.DefaultNamespace,
+			Type:      omni.ClusterMachineStatusType,
+			Kind:      controller.InputWeak,
+		},
+	}
+}
+
+// Outputs implements controller.Controller interface.
+func (ctrl *EtcdMaintenanceController) Outputs() []controller.Output {
+	return []controller.Output{
+		{
+			Type: omni.EtcdMaintenanceStatusType,
+			Kind: controller.OutputExclusive,
+		},
+	}
+}
+
+// Run implements controller.Controller interface.
+func (ctrl *EtcdMaintenanceController) Run(ctx context.Context, r controller.Runtime, logger *zap.Logger) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-r.EventCh():
+		}
+
+		configs, err := safe.ReaderListAll[*omni.EtcdMaintenanceConfig](ctx, r)
+		if err != nil {
+			return err
+		}
+
+		for config := range configs.All() {
+			if !config.TypedSpec().Value.AutoDefragEnabled {
+				continue
+			}
+
+			clusterName := config.Metadata().ID()
+
+			due, dueErr := ctrl.dueForRun(ctx, r, clusterName, config.TypedSpec().Value.Schedule)
+			if dueErr != nil {
+				logger.Warn("invalid etcd maintenance schedule", zap.String("cluster", clusterName), zap.Error(dueErr))
+
+				continue
+			}
+
+			if !due {
+				continue
+			}
+
+			if err = ctrl.reconcileCluster(ctx, r, logger, config); err != nil {
+				logger.Warn("etcd maintenance run failed", zap.String("cluster", clusterName), zap.Error(err))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(time.Minute):
+		}
+	}
+}
+
+// dueForRun reports whether clusterName's maintenance run is due according to schedule (a standard
+// 5-field cron expression). An empty schedule means "every reconcile", matching the pre-Schedule
+// behavior. It compares against the last recorded run time in EtcdMaintenanceStatus, so it survives
+// controller restarts.
+func (ctrl *EtcdMaintenanceController) dueForRun(ctx context.Context, r controller.Reader, clusterName, schedule string) (bool, error) {
+	if schedule == "" {
+		return true, nil
+	}
+
+	sched, err := cron.ParseStandard(schedule)
+	if err != nil {
+		return false, fmt.Errorf("invalid schedule %q: %w", schedule, err)
+	}
+
+	prevStatus, err := safe.ReaderGet[*omni.EtcdMaintenanceStatus](ctx, r, omni.NewEtcdMaintenanceStatus(resources.DefaultNamespace, clusterName).Metadata())
+	if err != nil {
+		if state.IsNotFoundError(err) {
+			return true, nil
+		}
+
+		return false, err
+	}
+
+	lastRun, err := time.Parse(time.RFC3339, prevStatus.TypedSpec().Value.LastRunTime)
+	if err != nil {
+		return true, nil
+	}
+
+	return !time.Now().Before(sched.Next(lastRun)), nil
+}
+
+func (ctrl *EtcdMaintenanceController) reconcileCluster(ctx context.Context, r controller.Runtime, logger *zap.Logger, config *omni.EtcdMaintenanceConfig) error {
+	clusterName := config.Metadata().ID()
+
+	threshold := config.TypedSpec().Value.FragmentationRatioThreshold
+	if threshold == 0 {
+		threshold = defaultFragmentationRatioThreshold
+	}
+
+	clusterMachineStatuses, err := safe.ReaderListAll[*omni.ClusterMachineStatus](ctx, r, state.WithLabelQuery(
+		resource.LabelEqual(omni.LabelCluster, clusterName),
+		resource.LabelExists(omni.LabelControlPlaneRole),
+	))
+	if err != nil {
+		return err
+	}
+
+	results := map[string]string{}
+
+	for status := range clusterMachineStatuses.All() {
+		machineID := status.Metadata().ID()
+
+		talosClient, clientErr := check.BuildTalosClient(ctx, r, clusterName, status)
+		if clientErr != nil {
+			results[machineID] = clientErr.Error()
+
+			continue
+		}
+
+		maintainErr := ctrl.maintainMember(ctx, talosClient, threshold, machineID, results)
+
+		talosClient.Close() //nolint:errcheck
+
+		if maintainErr != nil {
+			logger.Warn("etcd member maintenance failed", zap.String("cluster", clusterName), zap.String("machine", machineID), zap.Error(maintainErr))
+
+			continue
+		}
+
+		// defrag one member at a time: wait for etcd to report healthy again before touching the next
+		// member, so a defrag never takes more than one member out of the quorum picture at once.
+		if waitErr := ctrl.waitForHealthRecovery(ctx, r, clusterName); waitErr != nil {
+			logger.Warn("etcd did not recover health after defrag, pausing maintenance run",
+				zap.String("cluster", clusterName), zap.String("machine", machineID), zap.Error(waitErr))
+
+			results[machineID] = fmt.Sprintf("%s; health did not recover: %s", results[machineID], waitErr.Error())
+
+			break
+		}
+	}
+
+	_, err = safe.WriterModify(ctx, r, omni.NewEtcdMaintenanceStatus(resources.DefaultNamespace, clusterName),
+		func(res *omni.EtcdMaintenanceStatus) error {
+			res.TypedSpec().Value.LastRunTime = time.Now().Format(time.RFC3339)
+			res.TypedSpec().Value.MemberResults = results
+
+			return nil
+		},
+	)
+
+	return err
+}
+
+// waitForHealthRecovery polls etcd health until it reports healthy again or etcdHealthRecoveryTimeout
+// elapses.
+//
+// It uses check.EtcdMemberHealth rather than check.Etcd: check.Etcd asserts the member count is at its
+// "optimal" 2*quorum-1 value, which a control plane with a learner mid-join (see etcd_learner.go)
+// never satisfies, so using it here would time out and abort maintenance on every run for such a
+// cluster even though etcd is perfectly healthy.
+func (ctrl *EtcdMaintenanceController) waitForHealthRecovery(ctx context.Context, r controller.Reader, clusterName string) error {
+	deadline := time.Now().Add(etcdHealthRecoveryTimeout)
+
+	var lastErr error
+
+	for {
+		if lastErr = check.EtcdMemberHealth(ctx, r, clusterName); lastErr == nil {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("etcd did not recover within %s: %w", etcdHealthRecoveryTimeout, lastErr)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(etcdHealthRecoveryPollInterval):
+		}
+	}
+}
+
+func (ctrl *EtcdMaintenanceController) maintainMember(ctx context.Context, talosClient *client.Client, threshold float64, machineID string, results map[string]string) error {
+	etcdStatus, err := talosClient.EtcdStatus(ctx)
+	if err != nil {
+		results[machineID] = err.Error()
+
+		return err
+	}
+
+	var dbSize, dbSizeInUse int64
+
+	for _, message := range etcdStatus.GetMessages() {
+		dbSize = message.GetMemberStatus().GetDbSize()
+		dbSizeInUse = message.GetMemberStatus().GetDbSizeInUse()
+	}
+
+	if dbSize == 0 || float64(dbSizeInUse)/float64(dbSize) >= 1-threshold {
+		results[machineID] = "skipped: fragmentation below threshold"
+
+		return nil
+	}
+
+	if _, err = talosClient.EtcdDefragment(ctx, &machine.EtcdDefragmentRequest{}); err != nil {
+		results[machineID] = fmt.Sprintf("defrag failed: %s", err.Error())
+
+		return err
+	}
+
+	alarms, err := talosClient.EtcdAlarmList(ctx, &machine.EtcdAlarmListRequest{})
+	if err != nil {
+		results[machineID] = fmt.Sprintf("defrag ok, alarm list failed: %s", err.Error())
+
+		return err
+	}
+
+	var hasNoSpaceAlarm bool
+
+	for _, message := range alarms.GetMessages() {
+		for _, alarm := range message.GetAlarms() {
+			if alarm.GetAlarm() == machine.EtcdAlarmType_NOSPACE {
+				hasNoSpaceAlarm = true
+			}
+		}
+	}
+
+	if hasNoSpaceAlarm {
+		if _, err = talosClient.EtcdAlarmDisarm(ctx, &machine.EtcdAlarmDisarmRequest{}); err != nil {
+			results[machineID] = fmt.Sprintf("defrag ok, alarm disarm failed: %s", err.Error())
+
+			return err
+		}
+	}
+
+	results[machineID] = "defragmented"
+
+	return nil
+}
+
+var _ controller.Controller = &EtcdMaintenanceController{}
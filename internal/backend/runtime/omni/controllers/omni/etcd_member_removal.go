@@ -0,0 +1,244 @@
+// Copyright (c) 2025 Sidero Labs, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the LICENSE file.
+
+package omni
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/siderolabs/talos/pkg/machinery/api/machine"
+	talosclient "github.com/siderolabs/talos/pkg/machinery/client"
+	"go.uber.org/zap"
+
+	"github.com/siderolabs/omni/client/pkg/omni/resources"
+	"github.com/siderolabs/omni/client/pkg/omni/resources/omni"
+	"github.com/siderolabs/omni/internal/backend/runtime/omni/pkg/check"
+)
+
+// etcdMachineRemovalHookValue is the value of the omni.MachineRemovalHookPending label that marks a
+// control plane machine as waiting on the etcd pre-removal hook.
+const etcdMachineRemovalHookValue = "etcd"
+
+// etcdMemberRemovalPollInterval bounds how long a removal can be stuck on ScaleDownIsLeader/WouldBreakQuorum
+// before being re-evaluated: the etcd/Talos state driving these decisions isn't reflected in any
+// watched Omni resource, so EventCh alone would never re-trigger a reconcile.
+const etcdMemberRemovalPollInterval = 15 * time.Second
+
+// EtcdMemberRemovalController implements the etcd pre-removal hook: it keeps a control plane
+// ClusterMachine that is being deleted alive until its etcd member has been safely transferred away
+// from (if it was the leader) and removed from the etcd cluster by another, healthy member.
+//
+//nolint:govet
+type EtcdMemberRemovalController struct{}
+
+// NewEtcdMemberRemovalController creates new EtcdMemberRemovalController.
+func NewEtcdMemberRemovalController() *EtcdMemberRemovalController {
+	return &EtcdMemberRemovalController{}
+}
+
+// Name implements controller.Controller interface.
+func (ctrl *EtcdMemberRemovalController) Name() string {
+	return "EtcdMemberRemovalController"
+}
+
+// Inputs implements controller.Controller interface.
+func (ctrl *EtcdMemberRemovalController) Inputs() []controller.Input {
+	return []controller.Input{
+		{
+			Namespace: resources.DefaultNamespace,
+			Type:      omni.ClusterMachineType,
+			Kind:      controller.InputWeak,
+		},
+		{
+			Namespace: resources.DefaultNamespace,
+			Type:      omni.ClusterMachineStatusType,
+			Kind:      controller.InputWeak,
+		},
+	}
+}
+
+// Outputs implements controller.Controller interface.
+func (ctrl *EtcdMemberRemovalController) Outputs() []controller.Output {
+	return nil
+}
+
+// Run implements controller.Controller interface.
+func (ctrl *EtcdMemberRemovalController) Run(ctx context.Context, r controller.Runtime, logger *zap.Logger) error {
+	// etcd/Talos state (leadership, member health) can change a pending removal's outcome without
+	// ever touching a watched Omni resource, so poll on top of EventCh instead of relying on it alone.
+	ticker := time.NewTicker(etcdMemberRemovalPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-r.EventCh():
+		case <-ticker.C:
+		}
+
+		pending, err := safe.ReaderListAll[*omni.ClusterMachine](ctx, r, state.WithLabelQuery(
+			resource.LabelEqual(omni.MachineRemovalHookPending, etcdMachineRemovalHookValue),
+			resource.LabelExists(omni.LabelControlPlaneRole),
+		))
+		if err != nil {
+			return err
+		}
+
+		for clusterMachine := range pending.All() {
+			if err = ctrl.handleRemoval(ctx, r, logger, clusterMachine); err != nil {
+				logger.Warn("etcd member removal hook failed", zap.String("machine", clusterMachine.Metadata().ID()), zap.Error(err))
+			}
+		}
+	}
+}
+
+func (ctrl *EtcdMemberRemovalController) handleRemoval(ctx context.Context, r controller.Runtime, logger *zap.Logger, clusterMachine *omni.ClusterMachine) error {
+	machineID := clusterMachine.Metadata().ID()
+
+	clusterName, ok := clusterMachine.Metadata().Labels().Get(omni.LabelCluster)
+	if !ok {
+		return fmt.Errorf("cluster machine %q doesn't have the %s label", machineID, omni.LabelCluster)
+	}
+
+	machineSetID, ok := clusterMachine.Metadata().Labels().Get(omni.LabelMachineSet)
+	if !ok {
+		return fmt.Errorf("cluster machine %q doesn't have the %s label", machineID, omni.LabelMachineSet)
+	}
+
+	machineSet, err := safe.ReaderGetByID[*omni.MachineSet](ctx, r, machineSetID)
+	if err != nil {
+		return err
+	}
+
+	status, err := check.EtcdStatus(ctx, r, machineSet)
+	if err != nil {
+		return err
+	}
+
+	if _, ok = status.Members[machineID]; !ok {
+		// the machine is no longer an etcd member, the hook is done.
+		return ctrl.clearHook(ctx, r, clusterMachine)
+	}
+
+	reason, scaleDownErr := check.CanScaleDown(status, clusterMachine)
+
+	switch reason {
+	case check.ScaleDownOK:
+	case check.ScaleDownIsLeader:
+		logger.Info("transferring etcd leadership before removal", zap.String("machine", machineID))
+
+		// EtcdForfeitLeadership only has an effect on the node it is issued against, so it must be
+		// dialed directly to the leader being removed, not to some other healthy member.
+		return ctrl.withMemberClient(ctx, r, clusterName, machineID, func(ctx context.Context, talosClient *talosclient.Client) error {
+			_, err = talosClient.EtcdForfeitLeadership(ctx, &machine.EtcdForfeitLeadershipRequest{})
+
+			return err
+		})
+	default:
+		logger.Info("cannot remove etcd member yet", zap.String("machine", machineID), zap.String("reason", string(reason)), zap.Error(scaleDownErr))
+
+		return nil
+	}
+
+	identity, err := safe.ReaderGet[*omni.ClusterMachineIdentity](ctx, r, omni.NewClusterMachineIdentity(resources.DefaultNamespace, machineID).Metadata())
+	if err != nil {
+		return err
+	}
+
+	memberID := identity.TypedSpec().Value.EtcdMemberId
+
+	if err = ctrl.withHealthyMemberClient(ctx, r, clusterName, machineID, status, func(ctx context.Context, talosClient *talosclient.Client) error {
+		if _, err = talosClient.EtcdMemberRemove(ctx, &machine.EtcdRemoveMemberByIDRequest{MemberId: memberID}); err != nil {
+			return err
+		}
+
+		list, err := talosClient.EtcdMemberList(ctx, &machine.EtcdMemberListRequest{})
+		if err != nil {
+			return err
+		}
+
+		for _, messages := range list.GetMessages() {
+			for _, m := range messages.GetMembers() {
+				if m.GetId() == memberID {
+					return fmt.Errorf("etcd member %d is still present after removal", memberID)
+				}
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return ctrl.clearHook(ctx, r, clusterMachine)
+}
+
+// withMemberClient runs fn against the Talos client dialed directly to machineID.
+func (ctrl *EtcdMemberRemovalController) withMemberClient(
+	ctx context.Context, r controller.Reader, clusterName, machineID string,
+	fn func(ctx context.Context, talosClient *talosclient.Client) error,
+) error {
+	machineStatus, err := safe.ReaderGet[*omni.ClusterMachineStatus](ctx, r, omni.NewClusterMachineStatus(resources.DefaultNamespace, machineID).Metadata())
+	if err != nil {
+		return err
+	}
+
+	talosClient, err := check.BuildTalosClient(ctx, r, clusterName, machineStatus)
+	if err != nil {
+		return err
+	}
+
+	defer talosClient.Close() //nolint:errcheck
+
+	return fn(ctx, talosClient)
+}
+
+// withHealthyMemberClient runs fn against the Talos client of a healthy control plane member that is
+// not the machine being removed.
+func (ctrl *EtcdMemberRemovalController) withHealthyMemberClient(
+	ctx context.Context, r controller.Reader, clusterName, excludeMachineID string, status *check.EtcdStatusResult,
+	fn func(ctx context.Context, talosClient *talosclient.Client) error,
+) error {
+	for candidateID, member := range status.Members {
+		if candidateID == excludeMachineID || !member.Healthy {
+			continue
+		}
+
+		candidateStatus, err := safe.ReaderGet[*omni.ClusterMachineStatus](ctx, r, omni.NewClusterMachineStatus(resources.DefaultNamespace, candidateID).Metadata())
+		if err != nil {
+			continue
+		}
+
+		talosClient, err := check.BuildTalosClient(ctx, r, clusterName, candidateStatus)
+		if err != nil {
+			continue
+		}
+
+		err = fn(ctx, talosClient)
+
+		talosClient.Close() //nolint:errcheck
+
+		return err
+	}
+
+	return fmt.Errorf("no healthy etcd member available to act on behalf of machine %q", excludeMachineID)
+}
+
+func (ctrl *EtcdMemberRemovalController) clearHook(ctx context.Context, r controller.Runtime, clusterMachine *omni.ClusterMachine) error {
+	return r.Modify(ctx, clusterMachine, func(res resource.Resource) error {
+		res.Metadata().Labels().Delete(omni.MachineRemovalHookPending)
+
+		return nil
+	})
+}
+
+var _ controller.Controller = &EtcdMemberRemovalController{}
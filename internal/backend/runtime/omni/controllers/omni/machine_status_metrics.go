@@ -17,22 +17,43 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 
+	"github.com/siderolabs/omni/client/api/omni/specs"
 	"github.com/siderolabs/omni/client/pkg/omni/resources"
 	"github.com/siderolabs/omni/client/pkg/omni/resources/infra"
 	"github.com/siderolabs/omni/client/pkg/omni/resources/omni"
 )
 
+// machineStage is the lifecycle stage a machine is currently classified under for the
+// omni_machines_by_stage metric.
+type machineStage string
+
+const (
+	machineStagePending      machineStage = "pending"
+	machineStageProvisioning machineStage = "provisioning"
+	machineStageRunning      machineStage = "running"
+	machineStageUpgrading    machineStage = "upgrading"
+	machineStageFailed       machineStage = "failed"
+)
+
 // MachineStatusMetricsController provides metrics based on ClusterStatus.
 //
 //nolint:govet
 type MachineStatusMetricsController struct {
-	versionsMu  sync.Mutex
-	versionsMap map[string]int32
+	versionsMu          sync.Mutex
+	versionsMap         map[string]int32
+	clusterCounts       map[string]int32
+	stageCounts         map[machineStage]int32
+	connectedStates     map[string]bool
+	recordedAllocations map[string]struct{}
 
 	metricsOnce                 sync.Once
 	metricNumMachines           prometheus.Gauge
 	metricNumConnectedMachines  prometheus.Gauge
 	metricNumMachinesPerVersion *prometheus.Desc
+	metricMachinesByStage       *prometheus.Desc
+	metricClusterMachines       *prometheus.Desc
+	metricAllocationDuration    prometheus.Histogram
+	metricConnectionFlaps       prometheus.Counter
 }
 
 // Name implements controller.Controller interface.
@@ -84,6 +105,31 @@ func (ctrl *MachineStatusMetricsController) initMetrics() {
 			[]string{"talos_version"},
 			nil,
 		)
+
+		ctrl.metricMachinesByStage = prometheus.NewDesc(
+			"omni_machines_by_stage",
+			"Number of machines in the instance by lifecycle stage.",
+			[]string{"stage"},
+			nil,
+		)
+
+		ctrl.metricClusterMachines = prometheus.NewDesc(
+			"omni_cluster_machines",
+			"Number of machines allocated to a cluster.",
+			[]string{"cluster"},
+			nil,
+		)
+
+		ctrl.metricAllocationDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "omni_machine_allocation_duration_seconds",
+			Help:    "Time it takes a machine to go from being registered to being allocated to a cluster.",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 14), // 1s .. ~2.3h
+		})
+
+		ctrl.metricConnectionFlaps = prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "omni_machine_connection_flaps_total",
+			Help: "Total number of times a machine has transitioned between connected and disconnected.",
+		})
 	})
 }
 
@@ -107,7 +153,13 @@ func (ctrl *MachineStatusMetricsController) Run(ctx context.Context, r controlle
 			return err
 		}
 
-		pendingMachines := pendingInfraMachines.Len()
+		pendingMachineIDs := map[string]struct{}{}
+
+		for m := range pendingInfraMachines.All() {
+			pendingMachineIDs[m.Metadata().ID()] = struct{}{}
+		}
+
+		pendingMachines := len(pendingMachineIDs)
 
 		list, err := safe.ReaderListAll[*omni.MachineStatus](
 			ctx,
@@ -121,23 +173,69 @@ func (ctrl *MachineStatusMetricsController) Run(ctx context.Context, r controlle
 
 		ctrl.versionsMu.Lock()
 		ctrl.versionsMap = map[string]int32{}
+		clusterCounts := map[string]int32{}
+		stageCounts := map[machineStage]int32{}
+
+		if ctrl.connectedStates == nil {
+			ctrl.connectedStates = map[string]bool{}
+		}
+
+		if ctrl.recordedAllocations == nil {
+			ctrl.recordedAllocations = map[string]struct{}{}
+		}
+
+		now := time.Now()
+		seen := map[string]struct{}{}
 
 		for ms := range list.All() {
+			id := ms.Metadata().ID()
+			seen[id] = struct{}{}
+
 			machines++
 
-			if ms.TypedSpec().Value.Connected {
+			connected := ms.TypedSpec().Value.Connected
+			if connected {
 				connectedMachines++
 			}
 
+			if prevConnected, ok := ctrl.connectedStates[id]; ok && prevConnected != connected {
+				ctrl.metricConnectionFlaps.Inc()
+			}
+
+			ctrl.connectedStates[id] = connected
+
 			if ms.TypedSpec().Value.TalosVersion != "" {
 				ctrl.versionsMap[ms.TypedSpec().Value.TalosVersion]++
 			}
 
-			if ms.TypedSpec().Value.Cluster != "" {
+			cluster := ms.TypedSpec().Value.Cluster
+			if cluster != "" {
 				allocatedMachines++
+
+				clusterCounts[cluster]++
+
+				if _, recorded := ctrl.recordedAllocations[id]; !recorded {
+					ctrl.metricAllocationDuration.Observe(now.Sub(ms.Metadata().Created()).Seconds())
+					ctrl.recordedAllocations[id] = struct{}{}
+				}
 			}
+
+			_, pending := pendingMachineIDs[id]
+
+			stageCounts[machineStageOf(pending, ms.TypedSpec().Value.Stage, cluster)]++
 		}
 
+		// forget machines that no longer exist so the caches don't grow unbounded.
+		for id := range ctrl.connectedStates {
+			if _, ok := seen[id]; !ok {
+				delete(ctrl.connectedStates, id)
+				delete(ctrl.recordedAllocations, id)
+			}
+		}
+
+		ctrl.clusterCounts = clusterCounts
+		ctrl.stageCounts = stageCounts
+
 		ctrl.versionsMu.Unlock()
 
 		ctrl.metricNumMachines.Set(float64(machines))
@@ -165,6 +263,30 @@ func (ctrl *MachineStatusMetricsController) Run(ctx context.Context, r controlle
 	}
 }
 
+// machineStageOf classifies a machine into a coarse lifecycle stage for the omni_machines_by_stage
+// metric, derived from MachineStatus.Stage and InfraMachine's pending-accept state (pending).
+func machineStageOf(pending bool, stage specs.MachineStatusSpec_MachineStage, cluster string) machineStage {
+	if pending {
+		return machineStagePending
+	}
+
+	if cluster == "" {
+		return machineStageProvisioning
+	}
+
+	switch stage {
+	case specs.MachineStatusSpec_RUNNING:
+		return machineStageRunning
+	case specs.MachineStatusSpec_BOOTING, specs.MachineStatusSpec_INSTALLING,
+		specs.MachineStatusSpec_REBOOTING, specs.MachineStatusSpec_MAINTENANCE:
+		// an allocated machine cycling through these stages is almost always mid Talos/Kubernetes
+		// upgrade, not actually down.
+		return machineStageUpgrading
+	default:
+		return machineStageFailed
+	}
+}
+
 // Describe implements prom.Collector interface.
 func (ctrl *MachineStatusMetricsController) Describe(ch chan<- *prometheus.Desc) {
 	prometheus.DescribeByCollect(ctrl, ch)
@@ -180,10 +302,20 @@ func (ctrl *MachineStatusMetricsController) Collect(ch chan<- prometheus.Metric)
 		ch <- prometheus.MustNewConstMetric(ctrl.metricNumMachinesPerVersion, prometheus.GaugeValue, float64(count), version)
 	}
 
+	for cluster, count := range ctrl.clusterCounts {
+		ch <- prometheus.MustNewConstMetric(ctrl.metricClusterMachines, prometheus.GaugeValue, float64(count), cluster)
+	}
+
+	for stage, count := range ctrl.stageCounts {
+		ch <- prometheus.MustNewConstMetric(ctrl.metricMachinesByStage, prometheus.GaugeValue, float64(count), string(stage))
+	}
+
 	ctrl.versionsMu.Unlock()
 
 	ctrl.metricNumMachines.Collect(ch)
 	ctrl.metricNumConnectedMachines.Collect(ch)
+	ctrl.metricAllocationDuration.Collect(ch)
+	ctrl.metricConnectionFlaps.Collect(ch)
 }
 
 var _ prometheus.Collector = &MachineStatusMetricsController{}
@@ -0,0 +1,221 @@
+// Copyright (c) 2025 Sidero Labs, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the LICENSE file.
+
+package omni_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/siderolabs/omni/client/api/omni/specs"
+	"github.com/siderolabs/omni/client/pkg/omni/resources"
+	"github.com/siderolabs/omni/client/pkg/omni/resources/omni"
+	omnictrl "github.com/siderolabs/omni/internal/backend/runtime/omni/controllers/omni"
+)
+
+type MachineStatusMetricsSuite struct {
+	OmniSuite
+}
+
+func (suite *MachineStatusMetricsSuite) TestReconcile() {
+	suite.startRuntime()
+
+	ctrl := &omnictrl.MachineStatusMetricsController{}
+
+	suite.Require().NoError(suite.runtime.RegisterController(ctrl))
+
+	running := omni.NewMachineStatus(resources.DefaultNamespace, "running-machine")
+	running.TypedSpec().Value.Connected = true
+	running.TypedSpec().Value.TalosVersion = "v1.7.0"
+	running.TypedSpec().Value.Cluster = "my-cluster"
+	running.TypedSpec().Value.Stage = specs.MachineStatusSpec_RUNNING
+
+	suite.Require().NoError(suite.state.Create(suite.ctx, running))
+
+	provisioning := omni.NewMachineStatus(resources.DefaultNamespace, "provisioning-machine")
+	provisioning.TypedSpec().Value.Connected = true
+	provisioning.TypedSpec().Value.TalosVersion = "v1.7.0"
+
+	suite.Require().NoError(suite.state.Create(suite.ctx, provisioning))
+
+	reg := prometheus.NewRegistry()
+	suite.Require().NoError(reg.Register(ctrl))
+
+	suite.Assert().Eventually(func() bool {
+		expected := `
+# HELP omni_cluster_machines Number of machines allocated to a cluster.
+# TYPE omni_cluster_machines gauge
+omni_cluster_machines{cluster="my-cluster"} 1
+`
+
+		return testutil.GatherAndCompare(reg, strings.NewReader(expected), "omni_cluster_machines") == nil
+	}, 5*time.Second, 100*time.Millisecond)
+
+	suite.Assert().Eventually(func() bool {
+		expected := `
+# HELP omni_machines_by_stage Number of machines in the instance by lifecycle stage.
+# TYPE omni_machines_by_stage gauge
+omni_machines_by_stage{stage="provisioning"} 1
+omni_machines_by_stage{stage="running"} 1
+`
+
+		return testutil.GatherAndCompare(reg, strings.NewReader(expected), "omni_machines_by_stage") == nil
+	}, 5*time.Second, 100*time.Millisecond)
+
+	// the allocation duration histogram should have observed exactly one sample, recorded the moment the
+	// machine first showed up with a cluster assigned.
+	suite.Assert().Eventually(func() bool {
+		return allocationSampleCount(reg) == 1
+	}, 5*time.Second, 100*time.Millisecond)
+}
+
+// TestStateTransitions drives a single machine through connect/disconnect cycles, an allocation, and a
+// series of lifecycle stages, asserting that the connection flap counter, allocation duration histogram,
+// and stage gauge all reflect those transitions rather than just a single reconcile's snapshot.
+func (suite *MachineStatusMetricsSuite) TestStateTransitions() {
+	suite.startRuntime()
+
+	ctrl := &omnictrl.MachineStatusMetricsController{}
+
+	suite.Require().NoError(suite.runtime.RegisterController(ctrl))
+
+	ms := omni.NewMachineStatus(resources.DefaultNamespace, "flaky-machine")
+	ms.TypedSpec().Value.Connected = true
+	ms.TypedSpec().Value.TalosVersion = "v1.7.0"
+	ms.TypedSpec().Value.Stage = specs.MachineStatusSpec_BOOTING
+
+	suite.Require().NoError(suite.state.Create(suite.ctx, ms))
+
+	reg := prometheus.NewRegistry()
+	suite.Require().NoError(reg.Register(ctrl))
+
+	suite.Assert().Eventually(func() bool {
+		return testutil.CollectAndCount(ctrl, "omni_machines") == 1
+	}, 5*time.Second, 100*time.Millisecond)
+
+	// BOOTING with no cluster assigned is still provisioning, not upgrading.
+	suite.Assert().Eventually(func() bool {
+		expected := `
+# HELP omni_machines_by_stage Number of machines in the instance by lifecycle stage.
+# TYPE omni_machines_by_stage gauge
+omni_machines_by_stage{stage="provisioning"} 1
+`
+
+		return testutil.GatherAndCompare(reg, strings.NewReader(expected), "omni_machines_by_stage") == nil
+	}, 5*time.Second, 100*time.Millisecond)
+
+	// disconnect, then reconnect: two transitions, so the flap counter should read 2.
+	//
+	// The controller compares connected states between reconciles, and reconciles at most once every 10s,
+	// so each change is waited on until a reconcile has seen it, with a timeout longer than that interval.
+	_, err := safe.StateUpdateWithConflicts(suite.ctx, suite.state, ms.Metadata(), func(res *omni.MachineStatus) error {
+		res.TypedSpec().Value.Connected = false
+
+		return nil
+	})
+	suite.Require().NoError(err)
+
+	suite.Assert().Eventually(func() bool {
+		expected := `
+# HELP omni_connected_machines Number of machines in the instance that are connected.
+# TYPE omni_connected_machines gauge
+omni_connected_machines 0
+`
+
+		return testutil.GatherAndCompare(reg, strings.NewReader(expected), "omni_connected_machines") == nil
+	}, 30*time.Second, 100*time.Millisecond)
+
+	_, err = safe.StateUpdateWithConflicts(suite.ctx, suite.state, ms.Metadata(), func(res *omni.MachineStatus) error {
+		res.TypedSpec().Value.Connected = true
+
+		return nil
+	})
+	suite.Require().NoError(err)
+
+	suite.Assert().Eventually(func() bool {
+		expected := `
+# HELP omni_machine_connection_flaps_total Total number of times a machine has transitioned between connected and disconnected.
+# TYPE omni_machine_connection_flaps_total counter
+omni_machine_connection_flaps_total 2
+`
+
+		return testutil.GatherAndCompare(reg, strings.NewReader(expected), "omni_machine_connection_flaps_total") == nil
+	}, 30*time.Second, 100*time.Millisecond)
+
+	// allocate the machine to a cluster: the allocation duration histogram should observe exactly once.
+	_, err = safe.StateUpdateWithConflicts(suite.ctx, suite.state, ms.Metadata(), func(res *omni.MachineStatus) error {
+		res.TypedSpec().Value.Cluster = "flappy-cluster"
+		res.TypedSpec().Value.Stage = specs.MachineStatusSpec_INSTALLING
+
+		return nil
+	})
+	suite.Require().NoError(err)
+
+	// INSTALLING on an allocated machine is the "upgrading" bucket.
+	suite.Assert().Eventually(func() bool {
+		expected := `
+# HELP omni_machines_by_stage Number of machines in the instance by lifecycle stage.
+# TYPE omni_machines_by_stage gauge
+omni_machines_by_stage{stage="upgrading"} 1
+`
+
+		return testutil.GatherAndCompare(reg, strings.NewReader(expected), "omni_machines_by_stage") == nil
+	}, 30*time.Second, 100*time.Millisecond)
+
+	suite.Assert().Eventually(func() bool {
+		return allocationSampleCount(reg) == 1
+	}, 30*time.Second, 100*time.Millisecond)
+
+	// an unrecognized stage on an allocated machine falls through to "failed".
+	_, err = safe.StateUpdateWithConflicts(suite.ctx, suite.state, ms.Metadata(), func(res *omni.MachineStatus) error {
+		res.TypedSpec().Value.Stage = specs.MachineStatusSpec_UNKNOWN
+
+		return nil
+	})
+	suite.Require().NoError(err)
+
+	suite.Assert().Eventually(func() bool {
+		expected := `
+# HELP omni_machines_by_stage Number of machines in the instance by lifecycle stage.
+# TYPE omni_machines_by_stage gauge
+omni_machines_by_stage{stage="failed"} 1
+`
+
+		return testutil.GatherAndCompare(reg, strings.NewReader(expected), "omni_machines_by_stage") == nil
+	}, 30*time.Second, 100*time.Millisecond)
+
+	// reconciling the still-allocated machine again (after the stage change above) doesn't re-observe the
+	// allocation duration histogram: it already fired once for this machine.
+	suite.Assert().Equal(uint64(1), allocationSampleCount(reg))
+}
+
+// allocationSampleCount returns the number of observations recorded by the allocation duration
+// histogram, as gathered from reg.
+func allocationSampleCount(reg *prometheus.Registry) uint64 {
+	families, err := reg.Gather()
+	if err != nil {
+		return 0
+	}
+
+	for _, family := range families {
+		if family.GetName() == "omni_machine_allocation_duration_seconds" && len(family.GetMetric()) == 1 {
+			return family.GetMetric()[0].GetHistogram().GetSampleCount()
+		}
+	}
+
+	return 0
+}
+
+func TestMachineStatusMetricsSuite(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, new(MachineStatusMetricsSuite))
+}
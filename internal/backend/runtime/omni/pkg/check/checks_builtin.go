@@ -0,0 +1,43 @@
+// Copyright (c) 2025 Sidero Labs, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the LICENSE file.
+
+package check
+
+import "context"
+
+// EtcdHealthCheck verifies that etcd members match across nodes and that the member count is optimal.
+//
+// It is the ControlPlaneCheck wrapper around the long-standing Etcd function.
+type EtcdHealthCheck struct{}
+
+// Name implements ControlPlaneCheck.
+func (*EtcdHealthCheck) Name() string {
+	return "EtcdHealth"
+}
+
+// Run implements ControlPlaneCheck.
+func (*EtcdHealthCheck) Run(ctx context.Context, r ClusterReader) ([]Condition, error) {
+	return conditionsFromError(Etcd(ctx, r.Reader, r.ClusterName))
+}
+
+// EtcdAlarmsCheck verifies that no control plane node has an active etcd alarm.
+//
+// It is the ControlPlaneCheck wrapper around the EtcdAlarms function.
+type EtcdAlarmsCheck struct{}
+
+// Name implements ControlPlaneCheck.
+func (*EtcdAlarmsCheck) Name() string {
+	return "EtcdAlarms"
+}
+
+// Run implements ControlPlaneCheck.
+func (*EtcdAlarmsCheck) Run(ctx context.Context, r ClusterReader) ([]Condition, error) {
+	return conditionsFromError(EtcdAlarms(ctx, r.Reader, r.ClusterName))
+}
+
+var (
+	_ ControlPlaneCheck = &EtcdHealthCheck{}
+	_ ControlPlaneCheck = &EtcdAlarmsCheck{}
+)
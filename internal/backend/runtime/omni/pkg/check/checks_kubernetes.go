@@ -0,0 +1,303 @@
+// Copyright (c) 2025 Sidero Labs, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the LICENSE file.
+
+package check
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/siderolabs/talos/pkg/machinery/config/generate/secrets"
+
+	"github.com/siderolabs/omni/client/api/omni/specs"
+	"github.com/siderolabs/omni/client/pkg/omni/resources"
+	"github.com/siderolabs/omni/client/pkg/omni/resources/omni"
+)
+
+// certificateExpiryWarningThreshold is how far ahead of a certificate's expiry the CertificateExpiry
+// check starts warning.
+const certificateExpiryWarningThreshold = 30 * 24 * time.Hour
+
+// kubeAPIServerPort is the port kube-apiserver listens on.
+const kubeAPIServerPort = "6443"
+
+// healthzProbeTimeout bounds a single kube-scheduler/kube-controller-manager /healthz probe.
+const healthzProbeTimeout = 5 * time.Second
+
+// healthzClient talks to the kube-scheduler/kube-controller-manager secure (HTTPS-only) healthz ports.
+// Those ports serve a self-signed serving certificate, and the check only cares whether the process
+// answers 200 on /healthz, not about its identity, so skipping verification here is intentional rather
+// than a plan to ever send credentials over this connection.
+var healthzClient = &http.Client{
+	Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, //nolint:gosec
+	},
+}
+
+// apiServerDialTimeout bounds a single kube-apiserver reachability dial.
+const apiServerDialTimeout = 5 * time.Second
+
+// managementHost extracts the bare host from a ClusterMachineStatus's Talos management address,
+// stripping the Talos API port if one is present.
+func managementHost(status *omni.ClusterMachineStatus) string {
+	address := status.TypedSpec().Value.ManagementAddress
+
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return address
+	}
+
+	return host
+}
+
+// controlPlaneMachineStatuses returns the ClusterMachineStatus resources of the control plane of clusterName.
+func controlPlaneMachineStatuses(ctx context.Context, r ClusterReader) (safe.List[*omni.ClusterMachineStatus], error) {
+	return safe.ReaderListAll[*omni.ClusterMachineStatus](ctx, r.Reader, state.WithLabelQuery(
+		resource.LabelEqual(omni.LabelCluster, r.ClusterName),
+		resource.LabelExists(omni.LabelControlPlaneRole),
+	))
+}
+
+// APIServerReachabilityCheck verifies that kube-apiserver accepts TCP connections on every control
+// plane node, by dialing it directly rather than asking Talos about it: kube-apiserver reachability is
+// exactly what a TCP dial from outside the node observes.
+type APIServerReachabilityCheck struct{}
+
+// NewAPIServerReachabilityCheck builds an APIServerReachabilityCheck.
+func NewAPIServerReachabilityCheck() *APIServerReachabilityCheck {
+	return &APIServerReachabilityCheck{}
+}
+
+// Name implements ControlPlaneCheck.
+func (*APIServerReachabilityCheck) Name() string { return "APIServerReachability" }
+
+// Run implements ControlPlaneCheck.
+func (*APIServerReachabilityCheck) Run(ctx context.Context, r ClusterReader) ([]Condition, error) {
+	statuses, err := controlPlaneMachineStatuses(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	for status := range statuses.All() {
+		host := managementHost(status)
+		if host == "" {
+			continue
+		}
+
+		dialCtx, cancel := context.WithTimeout(ctx, apiServerDialTimeout)
+
+		conn, dialErr := (&net.Dialer{}).DialContext(dialCtx, "tcp", net.JoinHostPort(host, kubeAPIServerPort))
+
+		cancel()
+
+		if dialErr != nil {
+			return conditionsFromError(newErrorf(specs.ControlPlaneStatusSpec_Condition_Error, false,
+				"kube-apiserver is not reachable on node %s: %s", status.Metadata().ID(), dialErr.Error()))
+		}
+
+		conn.Close() //nolint:errcheck
+	}
+
+	return nil, nil
+}
+
+// staticPodHealthzCheck is a ControlPlaneCheck that probes a Kubernetes static pod's /healthz
+// endpoint on its kubelet-local port. kube-scheduler and kube-controller-manager are Kubernetes static
+// pods rather than Talos-supervised host services, so Talos's ServiceInfo can't see them; the /healthz
+// endpoint each of them serves is the mechanism Kubernetes itself uses to report their health.
+type staticPodHealthzCheck struct {
+	name string
+	port string
+}
+
+func (c *staticPodHealthzCheck) Name() string { return c.name }
+
+func (c *staticPodHealthzCheck) Run(ctx context.Context, r ClusterReader) ([]Condition, error) {
+	statuses, err := controlPlaneMachineStatuses(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	for status := range statuses.All() {
+		host := managementHost(status)
+		if host == "" {
+			continue
+		}
+
+		machineID := status.Metadata().ID()
+
+		reqCtx, cancel := context.WithTimeout(ctx, healthzProbeTimeout)
+
+		url := fmt.Sprintf("https://%s/healthz", net.JoinHostPort(host, c.port))
+
+		req, reqErr := http.NewRequestWithContext(reqCtx, http.MethodGet, url, nil)
+		if reqErr != nil {
+			cancel()
+
+			return nil, reqErr
+		}
+
+		resp, doErr := healthzClient.Do(req)
+
+		cancel()
+
+		if doErr != nil {
+			return conditionsFromError(newErrorf(specs.ControlPlaneStatusSpec_Condition_Error, false,
+				"%s healthz probe failed on node %s: %s", c.name, machineID, doErr.Error()))
+		}
+
+		resp.Body.Close() //nolint:errcheck
+
+		if resp.StatusCode != http.StatusOK {
+			return conditionsFromError(newErrorf(specs.ControlPlaneStatusSpec_Condition_Error, false,
+				"%s healthz probe returned status %d on node %s", c.name, resp.StatusCode, machineID))
+		}
+	}
+
+	return nil, nil
+}
+
+// SchedulerHealthCheck verifies that kube-scheduler's /healthz endpoint reports healthy on every
+// control plane node.
+type SchedulerHealthCheck struct{ staticPodHealthzCheck }
+
+// NewSchedulerHealthCheck builds a SchedulerHealthCheck.
+func NewSchedulerHealthCheck() *SchedulerHealthCheck {
+	return &SchedulerHealthCheck{staticPodHealthzCheck{name: "SchedulerHealth", port: "10259"}}
+}
+
+// ControllerManagerHealthCheck verifies that kube-controller-manager's /healthz endpoint reports
+// healthy on every control plane node.
+type ControllerManagerHealthCheck struct{ staticPodHealthzCheck }
+
+// NewControllerManagerHealthCheck builds a ControllerManagerHealthCheck.
+func NewControllerManagerHealthCheck() *ControllerManagerHealthCheck {
+	return &ControllerManagerHealthCheck{staticPodHealthzCheck{name: "ControllerManagerHealth", port: "10257"}}
+}
+
+// CertificateExpiryCheck parses the cluster's PKI from ClusterSecrets and warns when a certificate is
+// within certificateExpiryWarningThreshold of expiring.
+type CertificateExpiryCheck struct{}
+
+// Name implements ControlPlaneCheck.
+func (*CertificateExpiryCheck) Name() string { return "CertificateExpiry" }
+
+// Run implements ControlPlaneCheck.
+func (*CertificateExpiryCheck) Run(ctx context.Context, r ClusterReader) ([]Condition, error) {
+	clusterSecrets, err := safe.ReaderGet[*omni.ClusterSecrets](ctx, r.Reader,
+		resource.NewMetadata(resources.DefaultNamespace, omni.ClusterSecretsType, r.ClusterName, resource.VersionUndefined))
+	if err != nil {
+		if state.IsNotFoundError(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	buffer, err := clusterSecrets.TypedSpec().Value.GetUncompressedData()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cluster secrets: %w", err)
+	}
+
+	defer buffer.Free()
+
+	bundle, err := secrets.LoadBundle(buffer.Data())
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode cluster secrets bundle: %w", err)
+	}
+
+	now := time.Now()
+
+	var conditions []Condition
+
+	for name, pemCert := range map[string][]byte{
+		"etcd CA":       bundle.Certs.Etcd.Crt,
+		"kubernetes CA": bundle.Certs.K8s.Crt,
+	} {
+		block, _ := pem.Decode(pemCert)
+		if block == nil {
+			continue
+		}
+
+		cert, parseErr := x509.ParseCertificate(block.Bytes)
+		if parseErr != nil {
+			continue
+		}
+
+		if remaining := cert.NotAfter.Sub(now); remaining < certificateExpiryWarningThreshold {
+			conditions = append(conditions, Condition{
+				Status:   specs.ControlPlaneStatusSpec_Condition_NotReady,
+				Severity: specs.ControlPlaneStatusSpec_Condition_Warning,
+				Message:  fmt.Sprintf("%s certificate expires in %s (at %s)", name, remaining.Round(time.Hour), cert.NotAfter),
+			})
+		}
+	}
+
+	return conditions, nil
+}
+
+// KubeletVersionSkewCheck compares each node's kubelet version against the cluster's target Kubernetes
+// version and warns on a mismatch.
+type KubeletVersionSkewCheck struct{}
+
+// Name implements ControlPlaneCheck.
+func (*KubeletVersionSkewCheck) Name() string { return "KubeletVersionSkew" }
+
+// Run implements ControlPlaneCheck.
+func (*KubeletVersionSkewCheck) Run(ctx context.Context, r ClusterReader) ([]Condition, error) {
+	cluster, err := safe.ReaderGet[*omni.Cluster](ctx, r.Reader,
+		resource.NewMetadata(resources.DefaultNamespace, omni.ClusterType, r.ClusterName, resource.VersionUndefined))
+	if err != nil {
+		if state.IsNotFoundError(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	targetVersion := cluster.TypedSpec().Value.KubernetesVersion
+
+	statuses, err := safe.ReaderListAll[*omni.ClusterMachineStatus](ctx, r.Reader, state.WithLabelQuery(
+		resource.LabelEqual(omni.LabelCluster, r.ClusterName),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	var conditions []Condition
+
+	for status := range statuses.All() {
+		nodeVersion := status.TypedSpec().Value.KubernetesVersion
+		if nodeVersion == "" || nodeVersion == targetVersion {
+			continue
+		}
+
+		conditions = append(conditions, Condition{
+			Status:   specs.ControlPlaneStatusSpec_Condition_NotReady,
+			Severity: specs.ControlPlaneStatusSpec_Condition_Warning,
+			Message: fmt.Sprintf("node %s kubelet version %s doesn't match the control plane version %s",
+				status.Metadata().ID(), nodeVersion, targetVersion),
+		})
+	}
+
+	return conditions, nil
+}
+
+var (
+	_ ControlPlaneCheck = &APIServerReachabilityCheck{}
+	_ ControlPlaneCheck = &SchedulerHealthCheck{}
+	_ ControlPlaneCheck = &ControllerManagerHealthCheck{}
+	_ ControlPlaneCheck = &CertificateExpiryCheck{}
+	_ ControlPlaneCheck = &KubeletVersionSkewCheck{}
+)
@@ -33,6 +33,9 @@ type Error struct {
 	Status    specs.ControlPlaneStatusSpec_Condition_Status
 	Severity  specs.ControlPlaneStatusSpec_Condition_Severity
 	Interrupt bool
+	// LearnerPromoting is true if this error represents a learner that is still catching up before it
+	// can be promoted to a full voting etcd member, as opposed to any other check failure.
+	LearnerPromoting bool
 }
 
 // Error implements error interface.
@@ -49,6 +52,24 @@ func newErrorf(severity specs.ControlPlaneStatusSpec_Condition_Severity, interru
 	}
 }
 
+// newLearnerPromotingErrorf builds the dedicated LearnerPromoting condition surfaced while a learner
+// is still catching up, so callers can distinguish it from a hard etcd failure.
+func newLearnerPromotingErrorf(msg string, params ...any) error {
+	return &Error{
+		Status:           specs.ControlPlaneStatusSpec_Condition_NotReady,
+		Severity:         specs.ControlPlaneStatusSpec_Condition_Warning,
+		message:          fmt.Sprintf(msg, params...),
+		LearnerPromoting: true,
+	}
+}
+
+// IsLearnerPromoting reports whether err is the dedicated LearnerPromoting condition.
+func IsLearnerPromoting(err error) bool {
+	var checkErr *Error
+
+	return errors.As(err, &checkErr) && checkErr.LearnerPromoting
+}
+
 // Etcd checks that all etcd members are healthy and are in sync, etcd responds on all nodes.
 func Etcd(ctx context.Context, r controller.Reader, clusterName string) error {
 	clusterMachineStatuses, err := safe.ReaderListAll[*omni.ClusterMachineStatus](ctx, r, state.WithLabelQuery(
@@ -140,6 +161,11 @@ func checkEtcd(ctx context.Context, r controller.Reader, clusterName string, clu
 	return members, nil
 }
 
+// BuildTalosClient builds a Talos API client dialing the given control plane machines of the cluster.
+func BuildTalosClient(ctx context.Context, r controller.Reader, clusterName string, clusterMachineStatuses ...*omni.ClusterMachineStatus) (*client.Client, error) {
+	return buildTalosClient(ctx, r, clusterName, clusterMachineStatuses...)
+}
+
 func buildTalosClient(ctx context.Context, r controller.Reader, clusterName string, clusterMachineStatuses ...*omni.ClusterMachineStatus) (*client.Client, error) {
 	talosConfig, err := safe.ReaderGet[*omni.TalosConfig](ctx, r, resource.NewMetadata(resources.DefaultNamespace, omni.TalosConfigType, clusterName, resource.VersionUndefined))
 	if err != nil {
@@ -211,18 +237,55 @@ func checkEtcdStatus(ctx context.Context, talosClient *client.Client) error {
 	return nil
 }
 
+// EtcdMemberHealth checks that every control plane etcd member reports no errors and has no active
+// alarms, without asserting anything about the total member count.
+//
+// Unlike Etcd, this doesn't require the member count to be at the "optimal" 2*quorum-1 value: a
+// control plane with a learner mid-join (see AddLearnerMember) has an even member count as a normal,
+// healthy transient state, so callers that just need to know "did etcd recover after an operation"
+// (e.g. EtcdMaintenanceController between defrags) should use this instead of Etcd.
+func EtcdMemberHealth(ctx context.Context, r controller.Reader, clusterName string) error {
+	clusterMachineStatuses, err := safe.ReaderListAll[*omni.ClusterMachineStatus](ctx, r, state.WithLabelQuery(
+		resource.LabelEqual(omni.LabelCluster, clusterName),
+		resource.LabelExists(omni.LabelControlPlaneRole),
+	))
+	if err != nil {
+		return newErrorf(specs.ControlPlaneStatusSpec_Condition_Error, false, "Failed to get the list of machines %s", err.Error())
+	}
+
+	for item := range clusterMachineStatuses.All() {
+		talosClient, clientErr := buildTalosClient(ctx, r, clusterName, item)
+		if clientErr != nil {
+			return newErrorf(specs.ControlPlaneStatusSpec_Condition_Error, false, "Talos client is not ready on node %s", item.Metadata().ID())
+		}
+
+		err = checkEtcdStatus(ctx, talosClient)
+
+		talosClient.Close() //nolint:errcheck
+
+		if err != nil {
+			return newErrorf(specs.ControlPlaneStatusSpec_Condition_Error, false, "Etcd member health check failed on node %s: %s", item.Metadata().ID(), err.Error())
+		}
+	}
+
+	return EtcdAlarms(ctx, r, clusterName)
+}
+
 // CanScaleDown verifies that the machine can be safely removed from the control planes machine set.
-func CanScaleDown(status *EtcdStatusResult, machine resource.Resource) error {
+//
+// It returns a structured ScaleDownReason alongside the error so that callers can decide whether to
+// abort the removal or attempt remediation (e.g. transferring etcd leadership away first).
+func CanScaleDown(status *EtcdStatusResult, machine resource.Resource) (ScaleDownReason, error) {
 	member, ok := status.Members[machine.Metadata().ID()]
 	if !ok {
-		return nil
+		return ScaleDownOK, nil
 	}
 
 	totalMembers := len(status.Members)
 	healthyMembers := status.HealthyMembers
 
 	if healthyMembers < totalMembers/2+1 {
-		return fmt.Errorf("removing machine %q is not possible, etcd doesn't have quorum", machine.Metadata().ID())
+		return ScaleDownNoQuorum, fmt.Errorf("removing machine %q is not possible, etcd doesn't have quorum", machine.Metadata().ID())
 	}
 
 	totalMembers--
@@ -232,10 +295,14 @@ func CanScaleDown(status *EtcdStatusResult, machine resource.Resource) error {
 	}
 
 	if healthyMembers < totalMembers/2+1 {
-		return fmt.Errorf("removing machine %q will break etcd quorum", machine.Metadata().ID())
+		return ScaleDownWouldBreakQuorum, fmt.Errorf("removing machine %q will break etcd quorum", machine.Metadata().ID())
 	}
 
-	return nil
+	if member.IsLeader {
+		return ScaleDownIsLeader, fmt.Errorf("machine %q is the etcd leader, transfer leadership before removing it", machine.Metadata().ID())
+	}
+
+	return ScaleDownOK, nil
 }
 
 // EtcdStatusResult is the current etcd state: members count, healthy members count and the health of each member.
@@ -253,8 +320,24 @@ type EtcdMemberStatus struct {
 	Error string
 	// Healthy is the response from etcd service health request.
 	Healthy bool
+	// IsLeader is true if the member is the current etcd raft leader.
+	IsLeader bool
 }
 
+// ScaleDownReason describes why a control plane machine can or cannot be safely removed from etcd.
+type ScaleDownReason string
+
+const (
+	// ScaleDownOK means the machine can be safely removed from etcd.
+	ScaleDownOK ScaleDownReason = "OK"
+	// ScaleDownNoQuorum means etcd has already lost quorum, so no member can be safely removed.
+	ScaleDownNoQuorum ScaleDownReason = "NoQuorum"
+	// ScaleDownWouldBreakQuorum means removing the machine would take etcd below quorum.
+	ScaleDownWouldBreakQuorum ScaleDownReason = "WouldBreakQuorum"
+	// ScaleDownIsLeader means the machine is the current etcd leader and needs leadership transferred first.
+	ScaleDownIsLeader ScaleDownReason = "IsLeader"
+)
+
 // EtcdStatus reads control plane etcd members health.
 func EtcdStatus(ctx context.Context, r controller.Reader, machineSet *omni.MachineSet) (*EtcdStatusResult, error) {
 	if _, ok := machineSet.Metadata().Labels().Get(omni.LabelControlPlaneRole); !ok {
@@ -405,6 +488,22 @@ func getMemberState(ctx context.Context, talosConfig *omni.TalosConfig, clusterM
 		status.Healthy = info.Service.Health.Healthy
 	}
 
+	if !status.Healthy {
+		return status, nil
+	}
+
+	etcdStatus, err := c.EtcdStatus(ctx)
+	if err != nil {
+		// leadership info is best-effort: a failure here shouldn't fail the whole health check.
+		return status, nil
+	}
+
+	for _, message := range etcdStatus.GetMessages() {
+		memberStatus := message.GetMemberStatus()
+
+		status.IsLeader = memberStatus.GetMemberId() != 0 && memberStatus.GetMemberId() == memberStatus.GetLeader()
+	}
+
 	return status, nil
 }
 
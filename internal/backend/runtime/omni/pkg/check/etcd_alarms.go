@@ -0,0 +1,66 @@
+// Copyright (c) 2025 Sidero Labs, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the LICENSE file.
+
+package check
+
+import (
+	"context"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/siderolabs/talos/pkg/machinery/api/machine"
+
+	"github.com/siderolabs/omni/client/api/omni/specs"
+	"github.com/siderolabs/omni/client/pkg/omni/resources/omni"
+)
+
+// EtcdAlarms checks that no control plane node has an active etcd alarm (NOSPACE, CORRUPT, ...).
+func EtcdAlarms(ctx context.Context, r controller.Reader, clusterName string) error {
+	clusterMachineStatuses, err := safe.ReaderListAll[*omni.ClusterMachineStatus](ctx, r, state.WithLabelQuery(
+		resource.LabelEqual(omni.LabelCluster, clusterName),
+		resource.LabelExists(omni.LabelControlPlaneRole),
+	),
+	)
+	if err != nil {
+		return newErrorf(specs.ControlPlaneStatusSpec_Condition_Error, false, "failed to get the list of machines %s", err.Error())
+	}
+
+	for item := range clusterMachineStatuses.All() {
+		if err = checkEtcdAlarms(ctx, r, clusterName, item); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checkEtcdAlarms(ctx context.Context, r controller.Reader, clusterName string, clusterMachineStatus *omni.ClusterMachineStatus) error {
+	talosClient, err := buildTalosClient(ctx, r, clusterName, clusterMachineStatus)
+	if err != nil {
+		return err
+	}
+
+	defer talosClient.Close() //nolint:errcheck
+
+	resp, err := talosClient.EtcdAlarmList(ctx, &machine.EtcdAlarmListRequest{})
+	if err != nil {
+		return newErrorf(specs.ControlPlaneStatusSpec_Condition_Error, false, "failed to list etcd alarms on node %s: %s", clusterMachineStatus.Metadata().ID(), err.Error())
+	}
+
+	for _, message := range resp.GetMessages() {
+		for _, alarm := range message.GetAlarms() {
+			return newErrorf(
+				specs.ControlPlaneStatusSpec_Condition_Error,
+				false,
+				"etcd member %d on node %s has alarm %s",
+				alarm.GetMemberId(), clusterMachineStatus.Metadata().ID(), alarm.GetAlarm(),
+			)
+		}
+	}
+
+	return nil
+}
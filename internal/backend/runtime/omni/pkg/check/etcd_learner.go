@@ -0,0 +1,108 @@
+// Copyright (c) 2025 Sidero Labs, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the LICENSE file.
+
+package check
+
+import (
+	"context"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/siderolabs/talos/pkg/machinery/api/machine"
+	talosclient "github.com/siderolabs/talos/pkg/machinery/client"
+
+	"github.com/siderolabs/omni/client/api/omni/specs"
+	"github.com/siderolabs/omni/client/pkg/omni/resources"
+	"github.com/siderolabs/omni/client/pkg/omni/resources/omni"
+)
+
+// DefaultLearnerPromotionThreshold is the default maximum raft applied index lag (compared to the
+// leader's raft index) that a learner is allowed to have before it gets promoted to a voting member.
+const DefaultLearnerPromotionThreshold = 5000
+
+// AddLearnerMember adds a new etcd member as a learner rather than as a full voting member.
+//
+// Joining as a learner means the new member never counts towards quorum until it is explicitly
+// promoted, so a flaky or slow-to-catch-up join can never take a healthy cluster below quorum.
+func AddLearnerMember(ctx context.Context, r controller.ReaderWriter, talosClient *talosclient.Client, clusterMachineID, peerURL string) error {
+	resp, err := talosClient.EtcdMemberAdd(ctx, &machine.EtcdMemberAddRequest{
+		PeerAddrs: []string{peerURL},
+		IsLearner: true,
+	})
+	if err != nil {
+		return newErrorf(specs.ControlPlaneStatusSpec_Condition_Error, false, "failed to add etcd learner for machine %q: %s", clusterMachineID, err.Error())
+	}
+
+	var memberID uint64
+
+	for _, message := range resp.GetMessages() {
+		memberID = message.GetMember().GetId()
+	}
+
+	if memberID == 0 {
+		return newErrorf(specs.ControlPlaneStatusSpec_Condition_Error, false, "etcd didn't return a member id for the new learner on machine %q", clusterMachineID)
+	}
+
+	_, err = safe.WriterModify(ctx, r, omni.NewClusterMachineIdentity(resources.DefaultNamespace, clusterMachineID),
+		func(res *omni.ClusterMachineIdentity) error {
+			res.TypedSpec().Value.EtcdMemberId = memberID
+
+			return nil
+		},
+	)
+
+	return err
+}
+
+// PromoteLearner reads etcd status once and, if the learner identified by memberID has caught up with
+// the leader within threshold raft log entries, promotes it to a full voting member.
+//
+// If the learner is still further behind than threshold, it returns the LearnerPromoting error (see
+// IsLearnerPromoting) without promoting it; it does not wait or re-check the status itself.
+func PromoteLearner(ctx context.Context, talosClient *talosclient.Client, memberID uint64, threshold uint64) error {
+	status, err := talosClient.EtcdStatus(ctx)
+	if err != nil {
+		return newErrorf(specs.ControlPlaneStatusSpec_Condition_Error, false, "failed to read etcd status while promoting learner: %s", err.Error())
+	}
+
+	var (
+		leaderIndex  uint64
+		learnerIndex uint64
+		found        bool
+	)
+
+	for _, message := range status.GetMessages() {
+		memberStatus := message.GetMemberStatus()
+
+		if memberStatus.GetRaftIndex() > leaderIndex {
+			leaderIndex = memberStatus.GetRaftIndex()
+		}
+
+		if memberStatus.GetMemberId() == memberID {
+			learnerIndex = memberStatus.GetRaftAppliedIndex()
+			found = true
+		}
+	}
+
+	if !found {
+		return newErrorf(specs.ControlPlaneStatusSpec_Condition_Error, false, "etcd learner %d not found in the member status list", memberID)
+	}
+
+	// learnerIndex can be >= leaderIndex transiently (a racy read across members, or a leadership
+	// change between messages); guard the subtraction so that case is never treated as "behind" at all
+	// rather than underflowing to a huge uint64 that would permanently block promotion.
+	if learnerIndex < leaderIndex && leaderIndex-learnerIndex > threshold {
+		return newLearnerPromotingErrorf(
+			"etcd learner %d is still catching up: applied index %d is %d entries behind the leader",
+			memberID, learnerIndex, leaderIndex-learnerIndex,
+		)
+	}
+
+	if _, err = talosClient.EtcdMemberPromote(ctx, &machine.EtcdMemberPromoteRequest{MemberId: memberID}); err != nil {
+		return newErrorf(specs.ControlPlaneStatusSpec_Condition_Error, false, "failed to promote etcd learner %d: %s", memberID, err.Error())
+	}
+
+	return nil
+}
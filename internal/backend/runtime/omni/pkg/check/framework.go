@@ -0,0 +1,142 @@
+// Copyright (c) 2025 Sidero Labs, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the LICENSE file.
+
+package check
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+
+	"github.com/siderolabs/omni/client/api/omni/specs"
+)
+
+// ClusterReader is the read-only view of cluster state a ControlPlaneCheck needs to run.
+type ClusterReader struct {
+	controller.Reader
+
+	ClusterName string
+}
+
+// Condition is a single control plane health condition produced by a ControlPlaneCheck, tagged with
+// the name of the check that produced it so the UI can group conditions by check.
+type Condition struct {
+	CheckName string
+	Status    specs.ControlPlaneStatusSpec_Condition_Status
+	Severity  specs.ControlPlaneStatusSpec_Condition_Severity
+	Message   string
+}
+
+// ControlPlaneCheck is a single, independently configurable control plane health check.
+type ControlPlaneCheck interface {
+	// Name identifies the check, it is used to match it against ControlPlaneCheckConfig resources
+	// and to tag the conditions it produces.
+	Name() string
+	// Run executes the check against the given cluster and returns the conditions it observed.
+	//
+	// A nil, nil return means the check passed and has nothing to report.
+	Run(ctx context.Context, r ClusterReader) ([]Condition, error)
+}
+
+// Registry holds the set of known control plane checks and runs them, merging their conditions.
+type Registry struct {
+	checks []ControlPlaneCheck
+}
+
+// NewRegistry builds a Registry from the given checks.
+func NewRegistry(checks ...ControlPlaneCheck) *Registry {
+	return &Registry{checks: checks}
+}
+
+// DefaultRegistry returns the registry of checks shipped out of the box.
+func DefaultRegistry() *Registry {
+	return NewRegistry(
+		&EtcdHealthCheck{},
+		&EtcdAlarmsCheck{},
+		NewAPIServerReachabilityCheck(),
+		NewSchedulerHealthCheck(),
+		NewControllerManagerHealthCheck(),
+		&CertificateExpiryCheck{},
+		&KubeletVersionSkewCheck{},
+	)
+}
+
+// Register adds a check to the registry.
+func (reg *Registry) Register(c ControlPlaneCheck) {
+	reg.checks = append(reg.checks, c)
+}
+
+// CheckConfig is the per-check configuration looked up from omni.ControlPlaneCheckConfig.
+//
+// Enabled and SeverityOverride are enforced by Registry.Run. Interval is not: it is the caller's
+// responsibility (e.g. the controller scheduling Registry.Run) to only re-run a check after Interval
+// has elapsed since its last run.
+type CheckConfig struct {
+	Enabled          bool
+	SeverityOverride *specs.ControlPlaneStatusSpec_Condition_Severity
+	Interval         string
+}
+
+// Run executes every enabled check in the registry and returns the merged list of conditions.
+//
+// A single check failing is turned into a condition for that check rather than aborting the whole run,
+// so one flaky check can't hide the conditions already collected from the others: a *Error keeps its
+// own status and severity, and any other error becomes an Error severity condition.
+//
+// configs maps a check Name() to its CheckConfig; a check with no entry runs with its defaults enabled.
+func (reg *Registry) Run(ctx context.Context, r ClusterReader, configs map[string]CheckConfig) ([]Condition, error) {
+	var conditions []Condition
+
+	for _, c := range reg.checks {
+		cfg, hasConfig := configs[c.Name()]
+		if hasConfig && !cfg.Enabled {
+			continue
+		}
+
+		result, err := c.Run(ctx, r)
+		if err != nil {
+			result, err = conditionsFromError(err)
+			if err != nil {
+				result = []Condition{{
+					Status:   specs.ControlPlaneStatusSpec_Condition_NotReady,
+					Severity: specs.ControlPlaneStatusSpec_Condition_Error,
+					Message:  fmt.Sprintf("check %q failed: %s", c.Name(), err.Error()),
+				}}
+			}
+		}
+
+		for i := range result {
+			result[i].CheckName = c.Name()
+
+			if hasConfig && cfg.SeverityOverride != nil {
+				result[i].Severity = *cfg.SeverityOverride
+			}
+		}
+
+		conditions = append(conditions, result...)
+	}
+
+	return conditions, nil
+}
+
+// conditionsFromError converts a check *Error into a single Condition, or returns nil if err is nil.
+func conditionsFromError(err error) ([]Condition, error) {
+	if err == nil {
+		return nil, nil
+	}
+
+	var checkErr *Error
+	if errors.As(err, &checkErr) {
+		return []Condition{{
+			Status:   checkErr.Status,
+			Severity: checkErr.Severity,
+			Message:  checkErr.Error(),
+		}}, nil
+	}
+
+	return nil, err
+}